@@ -0,0 +1,137 @@
+// Package main implements a kubectl exec-credential plugin backed by
+// eksclient, so a kubeconfig can obtain EKS bearer tokens without embedding
+// IAM auth into a Go program or shelling out to aws-iam-authenticator.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/udhos/boilerplate/boilerplate"
+	"github.com/udhos/eks/eksclient"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/pkg/apis/clientauthentication/v1beta1"
+)
+
+func main() {
+	me := filepath.Base(os.Args[0])
+
+	clusterNameFlag := flag.String("cluster-name", "", "EKS cluster name, overridden by the cluster name carried in KUBERNETES_EXEC_INFO's spec.cluster.config, if present")
+	flag.Parse()
+
+	clusterName, interactive, errInfo := clusterFromExecInfo()
+	if errInfo != nil {
+		fail(fmt.Errorf("parse KUBERNETES_EXEC_INFO: %w", errInfo))
+	}
+
+	// kubectl invokes exec plugins with spec.interactive=false in
+	// non-interactive contexts (e.g. CI, controllers); stay quiet on stderr
+	// there instead of always printing the version banner.
+	if interactive {
+		log.Println(boilerplate.LongVersion(me))
+	}
+	if clusterName == "" {
+		clusterName = *clusterNameFlag
+	}
+	if clusterName == "" {
+		fail(fmt.Errorf("cluster name is required: set --cluster-name or run as a kubectl exec-credential plugin"))
+	}
+
+	if !interactive {
+		// aws-iam-authenticator's STS assume-role path reads an MFA code
+		// from os.Stdin unconditionally (StdinStderrTokenProvider), and
+		// kubectl runs exec plugins with spec.interactive=false and no
+		// attached TTY in exactly the automated contexts (CI, controllers)
+		// where nothing will ever answer that prompt. Point stdin at
+		// something that returns EOF immediately, so a credential that
+		// needs MFA fails fast instead of hanging forever.
+		devNull, errDevNull := os.Open(os.DevNull)
+		if errDevNull != nil {
+			fail(fmt.Errorf("open %s: %w", os.DevNull, errDevNull))
+		}
+		os.Stdin = devNull
+	}
+
+	source, errSource := eksclient.NewTokenSource(eksclient.Options{ClusterName: clusterName})
+	if errSource != nil {
+		fail(errSource)
+	}
+
+	tok, errGet := source.Get()
+	if errGet != nil {
+		fail(errGet)
+	}
+
+	cred := v1beta1.ExecCredential{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ExecCredential",
+			APIVersion: "client.authentication.k8s.io/v1beta1",
+		},
+		Status: &v1beta1.ExecCredentialStatus{
+			Token:               tok.Token,
+			ExpirationTimestamp: &metav1.Time{Time: tok.Expiration},
+		},
+	}
+
+	if err := json.NewEncoder(os.Stdout).Encode(&cred); err != nil {
+		fail(err)
+	}
+}
+
+// clusterFromExecInfo reads the KUBERNETES_EXEC_INFO env var kubectl sets
+// for exec plugins, if present, and extracts the cluster name carried in
+// spec.cluster.config, so a single binary can serve many clusters from one
+// kubeconfig. It also reports spec.interactive, which main uses to silence
+// the version banner and to neutralize stdin (so an MFA prompt can't hang
+// the process) on non-interactive invocations. When the env var is absent
+// (a human running the binary directly), interactive defaults to true.
+func clusterFromExecInfo() (clusterName string, interactive bool, err error) {
+	interactive = true
+
+	raw := os.Getenv("KUBERNETES_EXEC_INFO")
+	if raw == "" {
+		return "", interactive, nil
+	}
+
+	var info v1beta1.ExecCredential
+	if errUnmarshal := json.Unmarshal([]byte(raw), &info); errUnmarshal != nil {
+		return "", interactive, errUnmarshal
+	}
+
+	interactive = info.Spec.Interactive
+
+	if info.Spec.Cluster == nil || info.Spec.Cluster.Config.Raw == nil {
+		return "", interactive, nil
+	}
+
+	var cfg struct {
+		ClusterName string `json:"clusterName"`
+	}
+	if errUnmarshal := json.Unmarshal(info.Spec.Cluster.Config.Raw, &cfg); errUnmarshal != nil {
+		return "", interactive, errUnmarshal
+	}
+
+	return cfg.ClusterName, interactive, nil
+}
+
+// fail logs err, writes it to stderr as an ExecCredential-less Status error
+// object, and exits non-zero, per the exec-credential plugin protocol.
+func fail(err error) {
+	log.Printf("error: %v", err)
+	status := metav1.Status{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Status",
+			APIVersion: "v1",
+		},
+		Status:  metav1.StatusFailure,
+		Message: err.Error(),
+	}
+	if errEnc := json.NewEncoder(os.Stderr).Encode(&status); errEnc != nil {
+		log.Printf("encode status: %v", errEnc)
+	}
+	os.Exit(1)
+}
@@ -0,0 +1,49 @@
+package eksclient
+
+import (
+	"math/rand"
+	"time"
+)
+
+// backgroundRefresh proactively refreshes the token at
+// Expiration - RefreshEarlier - jitter, so foreground calls to Get almost
+// never block on an STS round trip. It runs until Close is called.
+func (g *tokenGenerator) backgroundRefresh() {
+	for {
+		g.lock.Lock()
+		expiration := g.last.Expiration
+		g.lock.Unlock()
+
+		timer := time.NewTimer(g.nextRefreshDelay(expiration))
+
+		select {
+		case <-timer.C:
+			// Refresh unconditionally rather than through Get, which would
+			// skip the STS call until Expiration - RefreshEarlier and so
+			// discard the jitter.
+			if _, err, _ := g.sf.Do(g.options.ClusterName, func() (any, error) {
+				return g.refresh()
+			}); err != nil {
+				g.options.debugf("backgroundRefresh: %v", err)
+			}
+		case <-g.stop:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// nextRefreshDelay returns how long to wait before the next background
+// refresh attempt, jittered by up to Options.RefreshJitter.
+func (g *tokenGenerator) nextRefreshDelay(expiration time.Time) time.Duration {
+	jitter := time.Duration(0)
+	if g.options.RefreshJitter > 0 {
+		jitter = time.Duration(rand.Int63n(int64(g.options.RefreshJitter)))
+	}
+
+	delay := time.Until(expiration.Add(-g.options.RefreshEarlier - jitter))
+	if delay <= 0 {
+		return time.Second
+	}
+	return delay
+}
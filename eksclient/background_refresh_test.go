@@ -0,0 +1,89 @@
+package eksclient
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/aws-iam-authenticator/pkg/token"
+)
+
+// countingTokenGetter hands back a token far from expiration and counts how
+// many times it was called, so a test can assert foreground Get calls did
+// not trigger an STS round trip after the background goroutine already
+// refreshed.
+type countingTokenGetter struct {
+	calls int32
+}
+
+func (g *countingTokenGetter) GetWithOptions(_ *token.GetTokenOptions) (token.Token, error) {
+	atomic.AddInt32(&g.calls, 1)
+	return token.Token{Token: "tok", Expiration: time.Now().Add(time.Hour)}, nil
+}
+
+// TestNewTokenSource_BackgroundRefreshForcesReuseToken guards the dependency
+// documented on Options.BackgroundRefresh: without ReuseToken, Get ignores
+// whatever the background goroutine already cached and refreshes on every
+// call anyway, defeating the whole point of BackgroundRefresh.
+func TestNewTokenSource_BackgroundRefreshForcesReuseToken(t *testing.T) {
+	source, err := NewTokenSource(Options{
+		ClusterName:       "test",
+		BackgroundRefresh: true,
+		ReuseToken:        false, // must be forced to true regardless
+	})
+	if err != nil {
+		t.Fatalf("NewTokenSource: %v", err)
+	}
+	defer source.(*tokenGenerator).Close()
+
+	if !source.(*tokenGenerator).options.ReuseToken {
+		t.Fatal("BackgroundRefresh=true did not force ReuseToken=true")
+	}
+}
+
+// TestTokenGenerator_BackgroundRefreshSuppressesForegroundSTSCalls proves
+// that once the background goroutine has populated g.last, foreground Get
+// calls reuse it instead of each re-hitting GetWithOptions.
+func TestTokenGenerator_BackgroundRefreshSuppressesForegroundSTSCalls(t *testing.T) {
+	gen := &countingTokenGetter{}
+
+	g := &tokenGenerator{
+		generator:    gen,
+		tokenOptions: &token.GetTokenOptions{ClusterID: "test"},
+		options: Options{
+			ClusterName:       "test",
+			ReuseToken:        true,
+			RefreshEarlier:    10 * time.Second,
+			BackgroundRefresh: true,
+			debugf:            func(string, ...any) {},
+		},
+		stop: make(chan struct{}),
+	}
+	go g.backgroundRefresh()
+	defer g.Close()
+
+	// Wait for the background goroutine to populate g.last. The first tick
+	// fires after nextRefreshDelay's 1-second fallback (g.last is still
+	// zero-valued, so the computed delay is negative), so give it a bit of
+	// headroom rather than racing that fallback exactly.
+	deadline := time.Now().Add(3 * time.Second)
+	for atomic.LoadInt32(&gen.calls) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if calls := atomic.LoadInt32(&gen.calls); calls == 0 {
+		t.Fatal("background goroutine never refreshed the token")
+	}
+
+	callsAfterBackground := atomic.LoadInt32(&gen.calls)
+
+	for i := 0; i < 10; i++ {
+		if _, err := g.Get(); err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+	}
+
+	if calls := atomic.LoadInt32(&gen.calls); calls != callsAfterBackground {
+		t.Fatalf("foreground Get issued %d extra STS calls after background refresh, want 0 (ReuseToken should have suppressed them)",
+			calls-callsAfterBackground)
+	}
+}
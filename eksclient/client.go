@@ -9,8 +9,10 @@ import (
 	"sync"
 	"time"
 
+	"golang.org/x/sync/singleflight"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/transport"
 	"sigs.k8s.io/aws-iam-authenticator/pkg/token"
 )
 
@@ -48,6 +50,26 @@ type Options struct {
 
 	// ReuseToken optionally adds layer to force token reuse. Usually redundant.
 	ReuseToken bool
+
+	// BackgroundRefresh optionally starts a goroutine that proactively
+	// refreshes the token ahead of expiration, so foreground calls to
+	// Get almost never block on an STS round trip. That guarantee only
+	// holds if Get actually reuses the cached token instead of
+	// unconditionally refreshing, so setting BackgroundRefresh implies
+	// ReuseToken: newTokenGenerator forces ReuseToken to true in that case,
+	// overriding whatever the caller set. The goroutine can only be stopped
+	// through the TokenSource returned by NewTokenSource (type assert it to
+	// an interface{ Close() } and call Close when done), so New rejects
+	// BackgroundRefresh when Options.Source is unset: it has no way to hand that TokenSource
+	// back to the caller, and would otherwise leak the goroutine for the
+	// life of the process. Build the source via NewTokenSource and set it
+	// as Options.Source instead.
+	BackgroundRefresh bool
+
+	// RefreshJitter optionally randomizes the background refresh time by up
+	// to this much, to avoid many clients refreshing in lockstep. Only
+	// meaningful when BackgroundRefresh is set.
+	RefreshJitter time.Duration
 }
 
 // TokenSource generates tokens.
@@ -56,12 +78,31 @@ type TokenSource interface {
 	Get() (token.Token, error)
 }
 
+// stsTokenGetter is the subset of token.Generator that tokenGenerator
+// actually calls. Narrowing it down from the full interface lets tests
+// substitute a fake that counts/delays calls, without having to implement
+// every method of token.Generator.
+type stsTokenGetter interface {
+	GetWithOptions(options *token.GetTokenOptions) (token.Token, error)
+}
+
 type tokenGenerator struct {
-	generator    token.Generator
+	generator    stsTokenGetter
 	tokenOptions *token.GetTokenOptions
 	last         token.Token
 	options      Options
 	lock         sync.Mutex
+	sf           singleflight.Group
+	stop         chan struct{}
+	stopOnce     sync.Once
+}
+
+// NewTokenSource creates the default STS-backed TokenSource, the same one
+// New uses internally when Options.Source is unset. It is exported for
+// callers that only need a token, such as exec-credential plugins, without
+// building a full clientset.
+func NewTokenSource(options Options) (TokenSource, error) {
+	return newTokenGenerator(applyDefaults(options))
 }
 
 func newTokenGenerator(options Options) (*tokenGenerator, error) {
@@ -69,33 +110,86 @@ func newTokenGenerator(options Options) (*tokenGenerator, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	if options.BackgroundRefresh {
+		// Get only skips the STS round trip when ReuseToken is set (see
+		// needsRefresh); without it, BackgroundRefresh keeps g.last warm
+		// for nothing, since every foreground Get still refreshes anyway.
+		options.ReuseToken = true
+	}
+
 	opts := &token.GetTokenOptions{
 		ClusterID: options.ClusterName,
 	}
-	return &tokenGenerator{
+	g := &tokenGenerator{
 		generator:    gen,
 		tokenOptions: opts,
 		options:      options,
-	}, nil
+	}
+
+	if options.BackgroundRefresh {
+		g.stop = make(chan struct{})
+		go g.backgroundRefresh()
+	}
+
+	return g, nil
 }
 
-// Get gets current token or generates a new one if the current one has expired or it is close to expire.
+// Get gets current token or generates a new one if the current one has
+// expired or it is close to expire. Concurrent callers that observe the
+// need for a refresh at the same time share the result of a single
+// in-flight GetWithOptions call instead of each issuing their own STS
+// request.
 func (g *tokenGenerator) Get() (token.Token, error) {
 	g.lock.Lock()
-	defer g.lock.Unlock()
-
 	now := time.Now()
-
 	g.debugToken(now, "old token")
+	refresh := g.needsRefresh(now)
+	cached := g.last
+	g.lock.Unlock()
 
-	if g.needsRefresh(now) {
-		tok, err := g.generator.GetWithOptions(g.tokenOptions)
-		g.last = tok
-		g.debugToken(now, "new token")
-		return tok, err
+	if !refresh {
+		return cached, nil
 	}
 
-	return g.last, nil
+	tok, err, _ := g.sf.Do(g.options.ClusterName, func() (any, error) {
+		return g.refresh()
+	})
+	if err != nil {
+		return token.Token{}, err
+	}
+	return tok.(token.Token), nil
+}
+
+// refresh issues the STS GetWithOptions call and stores the result. It is
+// only ever run once per singleflight key at a time.
+func (g *tokenGenerator) refresh() (token.Token, error) {
+	now := time.Now()
+
+	tok, err := g.generator.GetWithOptions(g.tokenOptions)
+	if err != nil {
+		return token.Token{}, err
+	}
+
+	g.lock.Lock()
+	g.last = tok
+	g.lock.Unlock()
+
+	g.debugToken(now, "new token")
+
+	return tok, nil
+}
+
+// Close stops the background refresh goroutine started when
+// Options.BackgroundRefresh is set. It is a no-op otherwise, and safe to
+// call more than once.
+func (g *tokenGenerator) Close() {
+	if g.stop == nil {
+		return
+	}
+	g.stopOnce.Do(func() {
+		close(g.stop)
+	})
 }
 
 func (g *tokenGenerator) needsRefresh(now time.Time) bool {
@@ -124,6 +218,26 @@ func (g *tokenGenerator) debugToken(now time.Time, label string) {
 // New creates kubernetes client.
 func New(options Options) (*kubernetes.Clientset, error) {
 
+	options = applyDefaults(options)
+
+	if options.Source == nil {
+		if options.BackgroundRefresh {
+			return nil, fmt.Errorf("eksclient.New: BackgroundRefresh requires building the token source via NewTokenSource and passing it as Options.Source, so the caller can Close it; New has no way to expose it")
+		}
+
+		source, err := newTokenGenerator(options)
+		if err != nil {
+			return nil, err
+		}
+		options.Source = source
+	}
+
+	return newClientset(options.debugf, options.Source, options.ClusterName, options.ClusterCAData, options.ClusterEndpoint)
+}
+
+// applyDefaults fills in the Options fields defaulted by New, for callers
+// that build a TokenSource without going through New (see NewTokenSource).
+func applyDefaults(options Options) Options {
 	if options.Logf == nil {
 		options.Logf = log.Printf
 	}
@@ -138,20 +252,14 @@ func New(options Options) (*kubernetes.Clientset, error) {
 		}
 	}
 
-	if options.Source == nil {
-		source, err := newTokenGenerator(options)
-		if err != nil {
-			return nil, err
-		}
-		options.Source = source
-	}
-
-	return newClientset(options.debugf, options.Source, options.ClusterName, options.ClusterCAData, options.ClusterEndpoint)
+	return options
 }
 
-// newClientset creates kubernetes client.
+// newClientset creates kubernetes client. extraWraps, if any, are applied on
+// top of the token-refreshing transport, outermost last (e.g. for metrics or
+// tracing layered in by callers such as Manager).
 func newClientset(debugf func(format string, v ...any), source TokenSource,
-	clusterName, clusterCAData, clusterEndpoint string) (*kubernetes.Clientset, error) {
+	clusterName, clusterCAData, clusterEndpoint string, extraWraps ...transport.WrapperFunc) (*kubernetes.Clientset, error) {
 
 	debugf("newClientset: clusterName=%s endpoint=%s CA=%s",
 		clusterName, clusterEndpoint, clusterCAData)
@@ -169,13 +277,11 @@ func newClientset(debugf func(format string, v ...any), source TokenSource,
 	}
 
 	// Adds a transport that refreshes the token when needed.
-	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
-		return &tokenTransport{
-			source:    source,
-			transport: rt,
-			debugf:    debugf,
-		}
-	})
+	config.Wrap(wrapTransport(source, debugf))
+
+	for _, wrap := range extraWraps {
+		config.Wrap(wrap)
+	}
 
 	clientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
@@ -0,0 +1,203 @@
+package eksclient
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// defaultDiscoverCacheTTL is used when DiscoverOptions.CacheTTL is left unset.
+const defaultDiscoverCacheTTL = 5 * time.Minute
+
+// DiscoverOptions define config for NewFromClusterName.
+type DiscoverOptions struct {
+	// ClusterName is the required EKS cluster name to discover.
+	ClusterName string
+
+	// Region optionally selects the AWS region used to build the AWS config.
+	// Ignored if AwsConfig is provided.
+	Region string
+
+	// Profile optionally selects the AWS shared config profile used to build
+	// the AWS config. Ignored if AwsConfig is provided.
+	Profile string
+
+	// AwsConfig optionally provides a pre-built AWS config, skipping the
+	// internal config lookup.
+	AwsConfig *aws.Config
+
+	// CacheTTL optionally sets how long a DescribeCluster result is cached
+	// before being fetched again. If unspecified, defaults to 5 minutes.
+	CacheTTL time.Duration
+
+	// ClientOptions optionally carries the remaining eksclient.Options fields
+	// (DebugLog, Logf, Source, RefreshEarlier, ReuseToken). ClusterName,
+	// ClusterCAData and ClusterEndpoint are filled in automatically from the
+	// DescribeCluster result, so any value set on those fields here is
+	// ignored.
+	ClientOptions Options
+}
+
+// Cluster bundles the kubernetes clientset built for a discovered EKS cluster
+// with the underlying *eks.Cluster metadata (tags, version, OIDC issuer,
+// etc), for callers that need more than just the clientset.
+type Cluster struct {
+	// Clientset is the kubernetes client built from the discovered cluster.
+	Clientset *kubernetes.Clientset
+
+	// Cluster is the raw EKS cluster description returned by DescribeCluster.
+	Cluster *ekstypes.Cluster
+}
+
+type discoverCacheEntry struct {
+	cluster   *ekstypes.Cluster
+	expiresAt time.Time
+}
+
+var (
+	discoverCacheLock sync.Mutex
+	discoverCache     = map[string]discoverCacheEntry{}
+)
+
+// eksDescribeClusterAPI is the subset of *eks.Client that discoverCluster
+// actually calls. Narrowing it down, and routing construction through
+// newEKSClient, lets tests substitute a fake DescribeCluster without
+// hitting real AWS, the same way stsTokenGetter does for token.Generator.
+type eksDescribeClusterAPI interface {
+	DescribeCluster(ctx context.Context, params *eks.DescribeClusterInput, optFns ...func(*eks.Options)) (*eks.DescribeClusterOutput, error)
+}
+
+// newEKSClient builds the eksDescribeClusterAPI used by discoverCluster. It
+// is a variable so tests can swap in a fake for the duration of a test.
+var newEKSClient = func(cfg aws.Config) eksDescribeClusterAPI {
+	return eks.NewFromConfig(cfg)
+}
+
+// discoverCacheKey identifies a cached DescribeCluster result. ClusterName
+// alone is not enough: the same cluster name can exist in different
+// accounts/regions (see Manager, which is meant for exactly that fan-out),
+// so the key also folds in Region, Profile and the identity of any
+// caller-provided AwsConfig.
+func discoverCacheKey(opts DiscoverOptions) string {
+	awsConfigID := "default"
+	if opts.AwsConfig != nil {
+		// Pointer identity: two distinct *aws.Config values (even if
+		// otherwise equal) are assumed to represent potentially different
+		// credentials and are never conflated. aws.Config itself cannot be
+		// used as a map key or hashed directly, since it holds
+		// incomparable fields (e.g. APIOptions).
+		awsConfigID = fmt.Sprintf("%p", opts.AwsConfig)
+	}
+	return opts.ClusterName + "|" + opts.Region + "|" + opts.Profile + "|" + awsConfigID
+}
+
+// NewFromClusterName discovers an EKS cluster by name via DescribeCluster,
+// and builds a kubernetes clientset from the discovered CA data and
+// endpoint, saving callers from duplicating that boilerplate. DescribeCluster
+// results are cached per cluster name and AWS identity (Region, Profile,
+// AwsConfig) for DiscoverOptions.CacheTTL so that
+// restart-loop callers don't hammer the EKS control plane.
+func NewFromClusterName(ctx context.Context, opts DiscoverOptions) (*Cluster, error) {
+	if opts.ClusterName == "" {
+		return nil, fmt.Errorf("eksclient.NewFromClusterName: ClusterName is required")
+	}
+	if opts.CacheTTL == 0 {
+		opts.CacheTTL = defaultDiscoverCacheTTL
+	}
+
+	cluster, err := discoverCluster(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	clientOptions := opts.ClientOptions
+	clientOptions.ClusterName = opts.ClusterName
+	clientOptions.ClusterCAData = aws.ToString(cluster.CertificateAuthority.Data)
+	clientOptions.ClusterEndpoint = aws.ToString(cluster.Endpoint)
+
+	clientset, err := New(clientOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cluster{Clientset: clientset, Cluster: cluster}, nil
+}
+
+// discoverCluster returns the EKS cluster description for opts.ClusterName,
+// reusing a cached result when it is still within its TTL. The cache is
+// keyed on the cluster name together with the AWS identity in opts (Region,
+// Profile, AwsConfig), so the same cluster name resolved against different
+// accounts/regions never shares an entry.
+func discoverCluster(ctx context.Context, opts DiscoverOptions) (*ekstypes.Cluster, error) {
+	key := discoverCacheKey(opts)
+
+	discoverCacheLock.Lock()
+	entry, found := discoverCache[key]
+	discoverCacheLock.Unlock()
+
+	if found && time.Now().Before(entry.expiresAt) {
+		return entry.cluster, nil
+	}
+
+	cfg, err := loadAwsConfig(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	client := newEKSClient(cfg)
+
+	out, err := client.DescribeCluster(ctx, &eks.DescribeClusterInput{Name: aws.String(opts.ClusterName)})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateClusterReady(out.Cluster); err != nil {
+		return nil, err
+	}
+
+	discoverCacheLock.Lock()
+	discoverCache[key] = discoverCacheEntry{
+		cluster:   out.Cluster,
+		expiresAt: time.Now().Add(opts.CacheTTL),
+	}
+	discoverCacheLock.Unlock()
+
+	return out.Cluster, nil
+}
+
+// validateClusterReady returns an error if cluster has no certificate
+// authority data yet, which EKS can return for a cluster description while
+// it is still CREATING. Without this check, dereferencing
+// CertificateAuthority.Data panics callers instead of returning an error.
+func validateClusterReady(cluster *ekstypes.Cluster) error {
+	if cluster.CertificateAuthority == nil || cluster.CertificateAuthority.Data == nil {
+		return fmt.Errorf("eksclient: cluster %q has no certificate authority data yet (status=%s)",
+			aws.ToString(cluster.Name), cluster.Status)
+	}
+	return nil
+}
+
+// loadAwsConfig returns opts.AwsConfig when provided, otherwise loads the
+// default AWS config honoring opts.Region and opts.Profile.
+func loadAwsConfig(ctx context.Context, opts DiscoverOptions) (aws.Config, error) {
+	if opts.AwsConfig != nil {
+		return *opts.AwsConfig, nil
+	}
+
+	var loadOpts []func(*awsconfig.LoadOptions) error
+	if opts.Region != "" {
+		loadOpts = append(loadOpts, awsconfig.WithRegion(opts.Region))
+	}
+	if opts.Profile != "" {
+		loadOpts = append(loadOpts, awsconfig.WithSharedConfigProfile(opts.Profile))
+	}
+
+	return awsconfig.LoadDefaultConfig(ctx, loadOpts...)
+}
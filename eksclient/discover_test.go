@@ -0,0 +1,294 @@
+package eksclient
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+)
+
+// fakeCAData builds a self-signed certificate, PEM- then base64-encodes it,
+// and returns it in the form EKS's CertificateAuthority.Data field carries
+// it, so rest.Config can parse it as a valid CA bundle.
+func fakeCAData(t *testing.T) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "fake-eks-ca"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+		KeyUsage:     x509.KeyUsageCertSign,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	return base64.StdEncoding.EncodeToString(certPEM)
+}
+
+// fakeEKSClient is a minimal eksDescribeClusterAPI that returns a canned
+// cluster/error and counts calls, so tests can assert cache hit/miss
+// behavior without hitting real AWS.
+type fakeEKSClient struct {
+	calls   int32
+	cluster *ekstypes.Cluster
+	err     error
+}
+
+func (f *fakeEKSClient) DescribeCluster(_ context.Context, _ *eks.DescribeClusterInput, _ ...func(*eks.Options)) (*eks.DescribeClusterOutput, error) {
+	atomic.AddInt32(&f.calls, 1)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &eks.DescribeClusterOutput{Cluster: f.cluster}, nil
+}
+
+// withFakeEKSClient swaps newEKSClient for the duration of the test,
+// restoring the real constructor on cleanup.
+func withFakeEKSClient(t *testing.T, fake *fakeEKSClient) {
+	t.Helper()
+	prev := newEKSClient
+	newEKSClient = func(aws.Config) eksDescribeClusterAPI { return fake }
+	t.Cleanup(func() { newEKSClient = prev })
+}
+
+func TestDiscoverCacheKey_DistinguishesAccounts(t *testing.T) {
+	accountA := &aws.Config{}
+	accountB := &aws.Config{}
+
+	cases := []struct {
+		name string
+		a    DiscoverOptions
+		b    DiscoverOptions
+	}{
+		{
+			name: "different profile, same cluster name",
+			a:    DiscoverOptions{ClusterName: "prod", Profile: "account-a"},
+			b:    DiscoverOptions{ClusterName: "prod", Profile: "account-b"},
+		},
+		{
+			name: "different region, same cluster name",
+			a:    DiscoverOptions{ClusterName: "prod", Region: "us-east-1"},
+			b:    DiscoverOptions{ClusterName: "prod", Region: "us-west-2"},
+		},
+		{
+			name: "different AwsConfig identity, same cluster name",
+			a:    DiscoverOptions{ClusterName: "prod", AwsConfig: accountA},
+			b:    DiscoverOptions{ClusterName: "prod", AwsConfig: accountB},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			keyA := discoverCacheKey(c.a)
+			keyB := discoverCacheKey(c.b)
+			if keyA == keyB {
+				t.Fatalf("expected distinct cache keys for different accounts, got %q for both", keyA)
+			}
+		})
+	}
+}
+
+func TestDiscoverCacheKey_SameIdentityReusesEntry(t *testing.T) {
+	cfg := &aws.Config{}
+	a := DiscoverOptions{ClusterName: "prod", Region: "us-east-1", Profile: "account-a", AwsConfig: cfg}
+	b := DiscoverOptions{ClusterName: "prod", Region: "us-east-1", Profile: "account-a", AwsConfig: cfg}
+
+	if discoverCacheKey(a) != discoverCacheKey(b) {
+		t.Fatalf("expected identical cache keys for identical DiscoverOptions")
+	}
+}
+
+func TestValidateClusterReady(t *testing.T) {
+	name := aws.String("prod")
+
+	t.Run("no certificate authority", func(t *testing.T) {
+		cluster := &ekstypes.Cluster{Name: name}
+		if err := validateClusterReady(cluster); err == nil {
+			t.Fatal("expected error for cluster with nil CertificateAuthority")
+		}
+	})
+
+	t.Run("no certificate authority data", func(t *testing.T) {
+		cluster := &ekstypes.Cluster{Name: name, CertificateAuthority: &ekstypes.Certificate{}}
+		if err := validateClusterReady(cluster); err == nil {
+			t.Fatal("expected error for cluster with nil CertificateAuthority.Data")
+		}
+	})
+
+	t.Run("ready", func(t *testing.T) {
+		cluster := &ekstypes.Cluster{
+			Name:                 name,
+			CertificateAuthority: &ekstypes.Certificate{Data: aws.String("ca-data")},
+		}
+		if err := validateClusterReady(cluster); err != nil {
+			t.Fatalf("unexpected error for ready cluster: %v", err)
+		}
+	})
+}
+
+func TestDiscoverCluster_CachesWithinTTL(t *testing.T) {
+	fake := &fakeEKSClient{
+		cluster: &ekstypes.Cluster{
+			Name:                 aws.String("prod"),
+			Endpoint:             aws.String("https://example.com"),
+			CertificateAuthority: &ekstypes.Certificate{Data: aws.String("ca-data")},
+		},
+	}
+	withFakeEKSClient(t, fake)
+
+	opts := DiscoverOptions{ClusterName: "prod", CacheTTL: time.Hour}
+	key := discoverCacheKey(opts)
+	t.Cleanup(func() {
+		discoverCacheLock.Lock()
+		delete(discoverCache, key)
+		discoverCacheLock.Unlock()
+	})
+
+	if _, err := discoverCluster(context.Background(), opts); err != nil {
+		t.Fatalf("discoverCluster (miss): %v", err)
+	}
+	if calls := atomic.LoadInt32(&fake.calls); calls != 1 {
+		t.Fatalf("expected 1 DescribeCluster call on a cache miss, got %d", calls)
+	}
+
+	if _, err := discoverCluster(context.Background(), opts); err != nil {
+		t.Fatalf("discoverCluster (hit): %v", err)
+	}
+	if calls := atomic.LoadInt32(&fake.calls); calls != 1 {
+		t.Fatalf("expected the second call to reuse the cache, got %d DescribeCluster calls", calls)
+	}
+}
+
+func TestDiscoverCluster_RefetchesAfterTTLExpires(t *testing.T) {
+	fake := &fakeEKSClient{
+		cluster: &ekstypes.Cluster{
+			Name:                 aws.String("prod"),
+			Endpoint:             aws.String("https://example.com"),
+			CertificateAuthority: &ekstypes.Certificate{Data: aws.String("ca-data")},
+		},
+	}
+	withFakeEKSClient(t, fake)
+
+	opts := DiscoverOptions{ClusterName: "prod", CacheTTL: time.Minute}
+	key := discoverCacheKey(opts)
+	t.Cleanup(func() {
+		discoverCacheLock.Lock()
+		delete(discoverCache, key)
+		discoverCacheLock.Unlock()
+	})
+
+	if _, err := discoverCluster(context.Background(), opts); err != nil {
+		t.Fatalf("discoverCluster (miss): %v", err)
+	}
+
+	// Force the cached entry to look expired without sleeping a minute.
+	discoverCacheLock.Lock()
+	entry := discoverCache[key]
+	entry.expiresAt = time.Now().Add(-time.Second)
+	discoverCache[key] = entry
+	discoverCacheLock.Unlock()
+
+	if _, err := discoverCluster(context.Background(), opts); err != nil {
+		t.Fatalf("discoverCluster (after expiry): %v", err)
+	}
+	if calls := atomic.LoadInt32(&fake.calls); calls != 2 {
+		t.Fatalf("expected a fresh DescribeCluster call once the TTL expired, got %d total calls", calls)
+	}
+}
+
+func TestDiscoverCluster_PropagatesDescribeClusterError(t *testing.T) {
+	wantErr := errors.New("access denied")
+	fake := &fakeEKSClient{err: wantErr}
+	withFakeEKSClient(t, fake)
+
+	opts := DiscoverOptions{ClusterName: "prod", CacheTTL: time.Hour}
+	if _, err := discoverCluster(context.Background(), opts); !errors.Is(err, wantErr) {
+		t.Fatalf("discoverCluster: err = %v, want %v", err, wantErr)
+	}
+}
+
+// TestNewFromClusterName_WiresDiscoveredClusterIntoOptions checks end-to-end,
+// without a real EKS/STS round trip, that NewFromClusterName builds a
+// Clientset from the discovered cluster (the fake CA data must decode and
+// parse for the build to succeed) and returns the DescribeCluster result,
+// endpoint included, as *Cluster.
+func TestNewFromClusterName_WiresDiscoveredClusterIntoOptions(t *testing.T) {
+	fake := &fakeEKSClient{
+		cluster: &ekstypes.Cluster{
+			Name:                 aws.String("prod"),
+			Endpoint:             aws.String("https://example.com"),
+			CertificateAuthority: &ekstypes.Certificate{Data: aws.String(fakeCAData(t))},
+		},
+	}
+	withFakeEKSClient(t, fake)
+
+	opts := DiscoverOptions{ClusterName: "prod", CacheTTL: time.Hour}
+	t.Cleanup(func() {
+		discoverCacheLock.Lock()
+		delete(discoverCache, discoverCacheKey(opts))
+		discoverCacheLock.Unlock()
+	})
+
+	result, err := NewFromClusterName(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("NewFromClusterName: %v", err)
+	}
+
+	if result.Clientset == nil {
+		t.Fatal("NewFromClusterName did not build a Clientset")
+	}
+	if aws.ToString(result.Cluster.Endpoint) != "https://example.com" {
+		t.Fatalf("Cluster.Endpoint = %q, want %q", aws.ToString(result.Cluster.Endpoint), "https://example.com")
+	}
+	if result.Clientset.RESTClient() == nil {
+		t.Fatal("Clientset has no REST client configured")
+	}
+}
+
+func TestNewFromClusterName_RejectsEmptyClusterName(t *testing.T) {
+	if _, err := NewFromClusterName(context.Background(), DiscoverOptions{}); err == nil {
+		t.Fatal("expected an error for an empty ClusterName")
+	}
+}
+
+func TestNewFromClusterName_PropagatesNotReadyError(t *testing.T) {
+	fake := &fakeEKSClient{
+		cluster: &ekstypes.Cluster{Name: aws.String("prod"), Status: ekstypes.ClusterStatusCreating},
+	}
+	withFakeEKSClient(t, fake)
+
+	opts := DiscoverOptions{ClusterName: "prod", CacheTTL: time.Hour}
+	t.Cleanup(func() {
+		discoverCacheLock.Lock()
+		delete(discoverCache, discoverCacheKey(opts))
+		discoverCacheLock.Unlock()
+	})
+
+	if _, err := NewFromClusterName(context.Background(), opts); err == nil {
+		t.Fatal("expected an error for a cluster with no certificate authority data yet")
+	}
+}
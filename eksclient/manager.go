@@ -0,0 +1,269 @@
+package eksclient
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/sync/singleflight"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/transport"
+	"sigs.k8s.io/aws-iam-authenticator/pkg/token"
+)
+
+// ManagerOptions define config for Manager.
+type ManagerOptions struct {
+	// Region optionally selects the AWS region used to build the AWS config
+	// shared by every managed cluster. Ignored if AwsConfig is provided.
+	Region string
+
+	// Profile optionally selects the AWS shared config profile used to
+	// build the AWS config shared by every managed cluster. Ignored if
+	// AwsConfig is provided.
+	Profile string
+
+	// AwsConfig optionally provides a pre-built AWS config, shared by every
+	// managed cluster, skipping the internal config lookup.
+	AwsConfig *aws.Config
+
+	// CacheTTL optionally sets how long a DescribeCluster result is cached
+	// per cluster before being fetched again. If unspecified, defaults to 5
+	// minutes.
+	CacheTTL time.Duration
+
+	// ClientOptions optionally carries the remaining eksclient.Options
+	// fields applied to every managed cluster (DebugLog, Logf,
+	// RefreshEarlier, ReuseToken, BackgroundRefresh, RefreshJitter).
+	// ClusterName, ClusterCAData, ClusterEndpoint and Source are managed
+	// internally and therefore ignored here.
+	ClientOptions Options
+
+	// Registerer optionally selects where the manager's metrics are
+	// registered. If unspecified, defaults to prometheus.DefaultRegisterer.
+	Registerer prometheus.Registerer
+}
+
+// Manager owns many kubernetes clientsets keyed by cluster name, sharing one
+// AWS config and reusing one token.Generator per cluster, so that fanning
+// out across many clusters does not spawn unrelated refresh loops or
+// DescribeCluster calls per clientset.
+type Manager struct {
+	opts ManagerOptions
+
+	lock     sync.Mutex
+	clusters map[string]*managedCluster
+	build    singleflight.Group
+
+	describeClusterCallsTotal prometheus.Counter
+	tokenRefreshTotal         *prometheus.CounterVec
+	roundtripDuration         *prometheus.HistogramVec
+}
+
+type managedCluster struct {
+	clientset *kubernetes.Clientset
+	source    *tokenGenerator
+}
+
+// NewManager creates a Manager and registers its metrics.
+func NewManager(opts ManagerOptions) *Manager {
+	if opts.Registerer == nil {
+		opts.Registerer = prometheus.DefaultRegisterer
+	}
+	if opts.CacheTTL == 0 {
+		opts.CacheTTL = defaultDiscoverCacheTTL
+	}
+
+	m := &Manager{
+		opts:     opts,
+		clusters: map[string]*managedCluster{},
+		describeClusterCallsTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "describe_cluster_calls_total",
+			Help: "Total number of EKS DescribeCluster calls issued by the manager.",
+		}),
+		tokenRefreshTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "token_refresh_total",
+			Help: "Total number of STS token refreshes, per cluster.",
+		}, []string{"cluster"}),
+		roundtripDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "roundtrip_duration_seconds",
+			Help: "Duration of Kubernetes API round trips, per cluster.",
+		}, []string{"cluster"}),
+	}
+
+	opts.Registerer.MustRegister(m.describeClusterCallsTotal, m.tokenRefreshTotal, m.roundtripDuration)
+
+	return m
+}
+
+// Clientset returns the kubernetes clientset for clusterName, lazily
+// discovering the cluster and building its clientset on first use.
+// Concurrent calls for the same cluster share a single build.
+func (m *Manager) Clientset(ctx context.Context, clusterName string) (*kubernetes.Clientset, error) {
+	m.lock.Lock()
+	mc, found := m.clusters[clusterName]
+	m.lock.Unlock()
+	if found {
+		return mc.clientset, nil
+	}
+
+	v, err, _ := m.build.Do(clusterName, func() (any, error) {
+		return m.buildClientset(ctx, clusterName)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*kubernetes.Clientset), nil
+}
+
+func (m *Manager) buildClientset(ctx context.Context, clusterName string) (*kubernetes.Clientset, error) {
+	m.lock.Lock()
+	if mc, found := m.clusters[clusterName]; found {
+		m.lock.Unlock()
+		return mc.clientset, nil
+	}
+	m.lock.Unlock()
+
+	discoverOptions := DiscoverOptions{
+		ClusterName: clusterName,
+		Region:      m.opts.Region,
+		Profile:     m.opts.Profile,
+		AwsConfig:   m.opts.AwsConfig,
+		CacheTTL:    m.opts.CacheTTL,
+	}
+
+	// Count only lookups that miss the shared cache, since only those issue
+	// a DescribeCluster call.
+	discoverCacheLock.Lock()
+	entry, found := discoverCache[discoverCacheKey(discoverOptions)]
+	discoverCacheLock.Unlock()
+	if !found || !time.Now().Before(entry.expiresAt) {
+		m.describeClusterCallsTotal.Inc()
+	}
+
+	cluster, err := discoverCluster(ctx, discoverOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	clientOptions := applyDefaults(m.opts.ClientOptions)
+	clientOptions.ClusterName = clusterName
+	clientOptions.ClusterCAData = aws.ToString(cluster.CertificateAuthority.Data)
+	clientOptions.ClusterEndpoint = aws.ToString(cluster.Endpoint)
+
+	source, err := newTokenGenerator(clientOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := newClientset(clientOptions.debugf, m.instrumentSource(clusterName, source),
+		clientOptions.ClusterName, clientOptions.ClusterCAData, clientOptions.ClusterEndpoint,
+		m.wrapRoundtripDuration(clusterName))
+	if err != nil {
+		return nil, err
+	}
+
+	m.lock.Lock()
+	m.clusters[clusterName] = &managedCluster{clientset: clientset, source: source}
+	m.lock.Unlock()
+
+	return clientset, nil
+}
+
+// Invalidate drops the cached clientset, token generator and DescribeCluster
+// result for clusterName, so the next Clientset call rediscovers it from
+// scratch.
+func (m *Manager) Invalidate(clusterName string) {
+	m.lock.Lock()
+	mc, found := m.clusters[clusterName]
+	delete(m.clusters, clusterName)
+	m.lock.Unlock()
+
+	if found {
+		mc.source.Close()
+	}
+
+	key := discoverCacheKey(DiscoverOptions{
+		ClusterName: clusterName,
+		Region:      m.opts.Region,
+		Profile:     m.opts.Profile,
+		AwsConfig:   m.opts.AwsConfig,
+	})
+
+	discoverCacheLock.Lock()
+	delete(discoverCache, key)
+	discoverCacheLock.Unlock()
+}
+
+// List returns the names of every cluster with a built clientset.
+func (m *Manager) List() []string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	names := make([]string, 0, len(m.clusters))
+	for name := range m.clusters {
+		names = append(names, name)
+	}
+	return names
+}
+
+// instrumentSource wraps source so that every token actually refreshed
+// (as opposed to served from cache) increments token_refresh_total for
+// clusterName.
+func (m *Manager) instrumentSource(clusterName string, source TokenSource) TokenSource {
+	return &instrumentedSource{
+		source:  source,
+		counter: m.tokenRefreshTotal.WithLabelValues(clusterName),
+	}
+}
+
+type instrumentedSource struct {
+	source  TokenSource
+	counter prometheus.Counter
+
+	lock      sync.Mutex
+	lastToken string
+}
+
+// Get implements TokenSource.
+func (s *instrumentedSource) Get() (token.Token, error) {
+	tok, err := s.source.Get()
+	if err != nil {
+		return tok, err
+	}
+
+	s.lock.Lock()
+	refreshed := tok.Token != s.lastToken
+	s.lastToken = tok.Token
+	s.lock.Unlock()
+
+	if refreshed {
+		s.counter.Inc()
+	}
+
+	return tok, nil
+}
+
+// wrapRoundtripDuration returns a transport.WrapperFunc that records the
+// duration of every Kubernetes API round trip for clusterName.
+func (m *Manager) wrapRoundtripDuration(clusterName string) transport.WrapperFunc {
+	observer := m.roundtripDuration.WithLabelValues(clusterName)
+	return func(rt http.RoundTripper) http.RoundTripper {
+		return &roundtripDurationTransport{next: rt, observer: observer}
+	}
+}
+
+type roundtripDurationTransport struct {
+	next     http.RoundTripper
+	observer prometheus.Observer
+}
+
+// RoundTrip times the wrapped transport and records it on observer.
+func (t *roundtripDurationTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	begin := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	t.observer.Observe(time.Since(begin).Seconds())
+	return resp, err
+}
@@ -0,0 +1,105 @@
+package eksclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// TestManager_CrossAccountCacheIsolation guards against two Managers for
+// different accounts (the exact assume-role fan-out Manager is built for)
+// cross-wiring a cached DescribeCluster result for a cluster name they
+// happen to share.
+func TestManager_CrossAccountCacheIsolation(t *testing.T) {
+	accountACfg := &aws.Config{}
+	accountBCfg := &aws.Config{}
+
+	optsA := DiscoverOptions{ClusterName: "prod", AwsConfig: accountACfg, CacheTTL: time.Minute}
+	optsB := DiscoverOptions{ClusterName: "prod", AwsConfig: accountBCfg, CacheTTL: time.Minute}
+
+	keyA := discoverCacheKey(optsA)
+	keyB := discoverCacheKey(optsB)
+
+	if keyA == keyB {
+		t.Fatalf("expected distinct cache keys for distinct accounts sharing a cluster name")
+	}
+
+	clusterA := &ekstypes.Cluster{
+		Name:                 aws.String("prod"),
+		Endpoint:             aws.String("https://a.example.com"),
+		CertificateAuthority: &ekstypes.Certificate{Data: aws.String("ca-a")},
+	}
+	clusterB := &ekstypes.Cluster{
+		Name:                 aws.String("prod"),
+		Endpoint:             aws.String("https://b.example.com"),
+		CertificateAuthority: &ekstypes.Certificate{Data: aws.String("ca-b")},
+	}
+
+	discoverCacheLock.Lock()
+	discoverCache[keyA] = discoverCacheEntry{cluster: clusterA, expiresAt: time.Now().Add(time.Minute)}
+	discoverCache[keyB] = discoverCacheEntry{cluster: clusterB, expiresAt: time.Now().Add(time.Minute)}
+	discoverCacheLock.Unlock()
+	t.Cleanup(func() {
+		discoverCacheLock.Lock()
+		delete(discoverCache, keyA)
+		delete(discoverCache, keyB)
+		discoverCacheLock.Unlock()
+	})
+
+	gotA, err := discoverCluster(context.Background(), optsA)
+	if err != nil {
+		t.Fatalf("discoverCluster(account A): %v", err)
+	}
+	if aws.ToString(gotA.Endpoint) != "https://a.example.com" {
+		t.Fatalf("account A got the wrong cached cluster: endpoint=%s", aws.ToString(gotA.Endpoint))
+	}
+
+	gotB, err := discoverCluster(context.Background(), optsB)
+	if err != nil {
+		t.Fatalf("discoverCluster(account B): %v", err)
+	}
+	if aws.ToString(gotB.Endpoint) != "https://b.example.com" {
+		t.Fatalf("account B got the wrong cached cluster: endpoint=%s", aws.ToString(gotB.Endpoint))
+	}
+}
+
+// TestManager_InvalidateUsesMatchingCacheKey guards against Invalidate
+// computing a different cache key than discoverCluster does, which would
+// leave a stale DescribeCluster result cached after Invalidate returns.
+func TestManager_InvalidateUsesMatchingCacheKey(t *testing.T) {
+	cfg := &aws.Config{}
+	m := NewManager(ManagerOptions{
+		Region:     "us-east-1",
+		Profile:    "account-a",
+		AwsConfig:  cfg,
+		Registerer: prometheus.NewRegistry(),
+	})
+
+	key := discoverCacheKey(DiscoverOptions{
+		ClusterName: "prod",
+		Region:      m.opts.Region,
+		Profile:     m.opts.Profile,
+		AwsConfig:   m.opts.AwsConfig,
+	})
+
+	discoverCacheLock.Lock()
+	discoverCache[key] = discoverCacheEntry{
+		cluster:   &ekstypes.Cluster{Name: aws.String("prod")},
+		expiresAt: time.Now().Add(time.Minute),
+	}
+	discoverCacheLock.Unlock()
+
+	m.Invalidate("prod")
+
+	discoverCacheLock.Lock()
+	_, found := discoverCache[key]
+	discoverCacheLock.Unlock()
+
+	if found {
+		t.Fatal("Invalidate did not remove the cache entry for this manager's cluster")
+	}
+}
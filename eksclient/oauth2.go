@@ -0,0 +1,75 @@
+package eksclient
+
+import (
+	"net/http"
+
+	"golang.org/x/oauth2"
+	"k8s.io/client-go/transport"
+	"sigs.k8s.io/aws-iam-authenticator/pkg/token"
+)
+
+// AsOAuth2 adapts an eksclient TokenSource to an oauth2.TokenSource, for
+// composing with the wider ecosystem of oauth2-based tooling.
+func AsOAuth2(source TokenSource) oauth2.TokenSource {
+	return oauth2Adapter{source: source}
+}
+
+type oauth2Adapter struct {
+	source TokenSource
+}
+
+// Token implements oauth2.TokenSource.
+func (a oauth2Adapter) Token() (*oauth2.Token, error) {
+	tok, err := a.source.Get()
+	if err != nil {
+		return nil, err
+	}
+	return &oauth2.Token{
+		AccessToken: tok.Token,
+		Expiry:      tok.Expiration,
+	}, nil
+}
+
+// FromOAuth2 adapts any oauth2.TokenSource (e.g. GCP, Azure workload
+// identity federation, or a static bearer token) into an eksclient
+// TokenSource suitable for Options.Source.
+func FromOAuth2(source oauth2.TokenSource) TokenSource {
+	return fromOAuth2Adapter{source: source}
+}
+
+type fromOAuth2Adapter struct {
+	source oauth2.TokenSource
+}
+
+// Get implements TokenSource.
+func (a fromOAuth2Adapter) Get() (token.Token, error) {
+	tok, err := a.source.Token()
+	if err != nil {
+		return token.Token{}, err
+	}
+	return token.Token{
+		Token:      tok.AccessToken,
+		Expiration: tok.Expiry,
+	}, nil
+}
+
+// WrapTransport returns a client-go transport.WrapperFunc that sets the
+// Authorization header from source on every request, refreshing it as
+// needed. It is analogous to client-go's transport.TokenSourceWrapTransport,
+// and can be composed with other WrapTransport layers (metrics, tracing,
+// impersonation).
+func WrapTransport(source TokenSource) transport.WrapperFunc {
+	return wrapTransport(source, func(string, ...any) {})
+}
+
+// wrapTransport is the internal version used by newClientset, which keeps
+// the debug-logging hook that the exported WrapTransport has no use for.
+func wrapTransport(source TokenSource, debugf func(format string, v ...any)) transport.WrapperFunc {
+	return func(rt http.RoundTripper) http.RoundTripper {
+		return &tokenTransport{
+			source:    source,
+			transport: rt,
+			debugf:    debugf,
+		}
+	}
+}
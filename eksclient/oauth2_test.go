@@ -0,0 +1,134 @@
+package eksclient
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+	"sigs.k8s.io/aws-iam-authenticator/pkg/token"
+)
+
+// fakeTokenSource is a TokenSource whose Get result (or error) is set
+// directly by the test.
+type fakeTokenSource struct {
+	tok token.Token
+	err error
+}
+
+func (f fakeTokenSource) Get() (token.Token, error) {
+	return f.tok, f.err
+}
+
+func TestAsOAuth2_Token(t *testing.T) {
+	exp := time.Now().Add(time.Hour)
+	source := fakeTokenSource{tok: token.Token{Token: "tok", Expiration: exp}}
+
+	got, err := AsOAuth2(source).Token()
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+	if got.AccessToken != "tok" {
+		t.Fatalf("AccessToken = %q, want %q", got.AccessToken, "tok")
+	}
+	if !got.Expiry.Equal(exp) {
+		t.Fatalf("Expiry = %v, want %v", got.Expiry, exp)
+	}
+}
+
+func TestAsOAuth2_PropagatesGetError(t *testing.T) {
+	wantErr := errors.New("sts unavailable")
+	source := fakeTokenSource{err: wantErr}
+
+	if _, err := AsOAuth2(source).Token(); !errors.Is(err, wantErr) {
+		t.Fatalf("Token: err = %v, want %v", err, wantErr)
+	}
+}
+
+// fakeOAuth2Source is an oauth2.TokenSource whose Token result (or error) is
+// set directly by the test.
+type fakeOAuth2Source struct {
+	tok *oauth2.Token
+	err error
+}
+
+func (f fakeOAuth2Source) Token() (*oauth2.Token, error) {
+	return f.tok, f.err
+}
+
+func TestFromOAuth2_Get(t *testing.T) {
+	exp := time.Now().Add(time.Hour)
+	source := fakeOAuth2Source{tok: &oauth2.Token{AccessToken: "tok", Expiry: exp}}
+
+	got, err := FromOAuth2(source).Get()
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Token != "tok" {
+		t.Fatalf("Token = %q, want %q", got.Token, "tok")
+	}
+	if !got.Expiration.Equal(exp) {
+		t.Fatalf("Expiration = %v, want %v", got.Expiration, exp)
+	}
+}
+
+func TestFromOAuth2_PropagatesTokenError(t *testing.T) {
+	wantErr := errors.New("token refresh failed")
+	source := fakeOAuth2Source{err: wantErr}
+
+	if _, err := FromOAuth2(source).Get(); !errors.Is(err, wantErr) {
+		t.Fatalf("Get: err = %v, want %v", err, wantErr)
+	}
+}
+
+// fakeRoundTripper records the last request it saw and returns a canned
+// response, so a test can assert what WrapTransport added to it.
+type fakeRoundTripper struct {
+	lastReq *http.Request
+}
+
+func (f *fakeRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.lastReq = req
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+}
+
+func TestWrapTransport_SetsAuthorizationHeader(t *testing.T) {
+	source := fakeTokenSource{tok: token.Token{Token: "tok", Expiration: time.Now().Add(time.Hour)}}
+	inner := &fakeRoundTripper{}
+
+	rt := WrapTransport(source)(inner)
+
+	req, err := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatalf("RoundTrip: %v", err)
+	}
+
+	if got := inner.lastReq.Header.Get("Authorization"); got != "Bearer tok" {
+		t.Fatalf("Authorization header = %q, want %q", got, "Bearer tok")
+	}
+}
+
+func TestWrapTransport_PropagatesGetError(t *testing.T) {
+	wantErr := errors.New("sts unavailable")
+	source := fakeTokenSource{err: wantErr}
+	inner := &fakeRoundTripper{}
+
+	rt := WrapTransport(source)(inner)
+
+	req, err := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+
+	if _, err := rt.RoundTrip(req); !errors.Is(err, wantErr) {
+		t.Fatalf("RoundTrip: err = %v, want %v", err, wantErr)
+	}
+	if inner.lastReq != nil {
+		t.Fatal("RoundTrip reached the inner transport despite Get failing")
+	}
+}
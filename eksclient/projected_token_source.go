@@ -0,0 +1,106 @@
+package eksclient
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/aws-iam-authenticator/pkg/token"
+)
+
+// defaultProjectedTokenPath is the path kubelet mounts a projected
+// ServiceAccount token at by default.
+const defaultProjectedTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+// ProjectedTokenSource is a TokenSource that reads a bearer token from a
+// projected ServiceAccount token file instead of calling STS, for pods that
+// already have one mounted (e.g. IRSA/Pod Identity setups). It re-reads the
+// file whenever the kubelet rewrites it or the cached token is close to
+// expiring.
+type ProjectedTokenSource struct {
+	path           string
+	refreshEarlier time.Duration
+
+	lock    sync.Mutex
+	last    token.Token
+	modTime time.Time
+}
+
+// NewProjectedTokenSource creates a ProjectedTokenSource reading from path.
+// If path is empty, it defaults to the standard projected-token mount path
+// (/var/run/secrets/kubernetes.io/serviceaccount/token).
+func NewProjectedTokenSource(path string, refreshEarlier time.Duration) *ProjectedTokenSource {
+	if path == "" {
+		path = defaultProjectedTokenPath
+	}
+	return &ProjectedTokenSource{
+		path:           path,
+		refreshEarlier: refreshEarlier,
+	}
+}
+
+// Get gets the current token, re-reading the token file if it was rotated by
+// the kubelet or if the cached token is within refreshEarlier of expiring.
+func (s *ProjectedTokenSource) Get() (token.Token, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return token.Token{}, fmt.Errorf("eksclient: stat projected token file %s: %w", s.path, err)
+	}
+
+	now := time.Now()
+	needsReload := s.last.Token == "" ||
+		!info.ModTime().Equal(s.modTime) ||
+		now.Add(s.refreshEarlier).After(s.last.Expiration)
+
+	if !needsReload {
+		return s.last, nil
+	}
+
+	raw, err := os.ReadFile(s.path)
+	if err != nil {
+		return token.Token{}, fmt.Errorf("eksclient: read projected token file %s: %w", s.path, err)
+	}
+
+	tok := strings.TrimSpace(string(raw))
+
+	exp, err := jwtExpiration(tok)
+	if err != nil {
+		return token.Token{}, fmt.Errorf("eksclient: parse projected token %s: %w", s.path, err)
+	}
+
+	s.last = token.Token{Token: tok, Expiration: exp}
+	s.modTime = info.ModTime()
+
+	return s.last, nil
+}
+
+// jwtExpiration decodes the unverified "exp" claim from a JWT payload. The
+// token is already trusted since it was mounted by the kubelet, so only the
+// expiration is needed here to drive the refresh-earlier logic.
+func jwtExpiration(raw string) (time.Time, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return time.Time{}, fmt.Errorf("malformed JWT: expected 3 segments, got %d", len(parts))
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("decode JWT payload: %w", err)
+	}
+
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return time.Time{}, fmt.Errorf("unmarshal JWT claims: %w", err)
+	}
+
+	return time.Unix(claims.Exp, 0), nil
+}
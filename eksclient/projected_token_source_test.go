@@ -0,0 +1,125 @@
+package eksclient
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// makeFakeJWT builds a minimal JWT (no signature verification is ever done
+// by ProjectedTokenSource) carrying the given expiration in its exp claim.
+func makeFakeJWT(t *testing.T, exp time.Time) string {
+	t.Helper()
+
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+
+	payload, err := json.Marshal(struct {
+		Exp int64 `json:"exp"`
+	}{Exp: exp.Unix()})
+	if err != nil {
+		t.Fatalf("marshal claims: %v", err)
+	}
+
+	return header + "." + base64.RawURLEncoding.EncodeToString(payload) + ".sig"
+}
+
+// writeTokenFile writes token content to path and forces its mtime so the
+// test does not depend on filesystem mtime resolution to observe a change.
+func writeTokenFile(t *testing.T, path, content string, mtime time.Time) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write token file: %v", err)
+	}
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatalf("chtimes token file: %v", err)
+	}
+}
+
+func TestProjectedTokenSource_PicksUpRotatedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+
+	base := time.Now()
+	firstExp := base.Add(time.Hour)
+	first := makeFakeJWT(t, firstExp)
+	writeTokenFile(t, path, first, base)
+
+	source := NewProjectedTokenSource(path, 0)
+
+	tok1, err := source.Get()
+	if err != nil {
+		t.Fatalf("Get (first read): %v", err)
+	}
+	if tok1.Token != first {
+		t.Fatalf("Get (first read): token = %q, want %q", tok1.Token, first)
+	}
+	if !tok1.Expiration.Equal(time.Unix(firstExp.Unix(), 0)) {
+		t.Fatalf("Get (first read): expiration = %v, want %v", tok1.Expiration, firstExp)
+	}
+
+	// Calling Get again without touching the file must not pick up a
+	// different token, since nothing rotated.
+	tok1Again, err := source.Get()
+	if err != nil {
+		t.Fatalf("Get (cached): %v", err)
+	}
+	if tok1Again.Token != tok1.Token {
+		t.Fatalf("Get (cached): token changed without a file rotation")
+	}
+
+	// Simulate the kubelet rotating the projected token mid-flight: new
+	// content, new mtime.
+	secondExp := base.Add(2 * time.Hour)
+	second := makeFakeJWT(t, secondExp)
+	writeTokenFile(t, path, second, base.Add(time.Second))
+
+	tok2, err := source.Get()
+	if err != nil {
+		t.Fatalf("Get (after rotation): %v", err)
+	}
+	if tok2.Token != second {
+		t.Fatalf("Get (after rotation): token = %q, want %q", tok2.Token, second)
+	}
+	if !tok2.Expiration.Equal(time.Unix(secondExp.Unix(), 0)) {
+		t.Fatalf("Get (after rotation): expiration = %v, want %v", tok2.Expiration, secondExp)
+	}
+}
+
+func TestProjectedTokenSource_RefreshesNearExpiration(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+
+	base := time.Now()
+	// Expiration is already within the refreshEarlier window, so Get must
+	// reload even though the file's mtime has not changed since.
+	expiringSoon := base.Add(time.Second)
+	first := makeFakeJWT(t, expiringSoon)
+	writeTokenFile(t, path, first, base)
+
+	source := NewProjectedTokenSource(path, time.Hour)
+
+	if _, err := source.Get(); err != nil {
+		t.Fatalf("Get (first read): %v", err)
+	}
+
+	renewedExp := base.Add(3 * time.Hour)
+	renewed := makeFakeJWT(t, renewedExp)
+	writeTokenFile(t, path, renewed, base) // same mtime on purpose
+
+	tok, err := source.Get()
+	if err != nil {
+		t.Fatalf("Get (near expiration): %v", err)
+	}
+	if tok.Token != renewed {
+		t.Fatalf("Get (near expiration): token = %q, want %q (expected reload despite unchanged mtime)", tok.Token, renewed)
+	}
+}
+
+func TestProjectedTokenSource_DefaultPath(t *testing.T) {
+	source := NewProjectedTokenSource("", time.Minute)
+	if source.path != defaultProjectedTokenPath {
+		t.Fatalf("path = %q, want default %q", source.path, defaultProjectedTokenPath)
+	}
+}
@@ -0,0 +1,100 @@
+package eksclient
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/aws-iam-authenticator/pkg/token"
+)
+
+// slowTokenGetter simulates an expensive signed STS round trip, counting how
+// many times it was actually invoked.
+type slowTokenGetter struct {
+	calls int32
+	delay time.Duration
+}
+
+func (g *slowTokenGetter) GetWithOptions(_ *token.GetTokenOptions) (token.Token, error) {
+	atomic.AddInt32(&g.calls, 1)
+	time.Sleep(g.delay)
+	return token.Token{Token: "tok", Expiration: time.Now().Add(time.Minute)}, nil
+}
+
+// TestTokenGenerator_SingleflightCoalescesConcurrentRefresh demonstrates
+// that 1000 concurrent Get calls racing at token-expiry time issue exactly
+// one underlying GetWithOptions call, instead of each serializing behind
+// its own STS round trip.
+func TestTokenGenerator_SingleflightCoalescesConcurrentRefresh(t *testing.T) {
+	gen := &slowTokenGetter{delay: 50 * time.Millisecond}
+
+	g := &tokenGenerator{
+		generator:    gen,
+		tokenOptions: &token.GetTokenOptions{ClusterID: "test"},
+		options: Options{
+			ClusterName: "test",
+			debugf:      func(string, ...any) {},
+		},
+	}
+
+	const concurrency = 1000
+
+	var ready sync.WaitGroup
+	ready.Add(concurrency)
+	start := make(chan struct{})
+
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+
+	errs := make(chan error, concurrency)
+
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			ready.Done()
+			<-start
+			if _, err := g.Get(); err != nil {
+				errs <- err
+			}
+		}()
+	}
+
+	ready.Wait()
+	close(start)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Fatalf("Get: %v", err)
+	}
+
+	if calls := atomic.LoadInt32(&gen.calls); calls != 1 {
+		t.Fatalf("expected exactly 1 GetWithOptions call for %d concurrent Get calls, got %d", concurrency, calls)
+	}
+}
+
+// BenchmarkTokenGenerator_ConcurrentRefresh exercises the same coalescing
+// path under the race detector / -cpu stress, without asserting a call
+// count (see the Test above for that assertion).
+func BenchmarkTokenGenerator_ConcurrentRefresh(b *testing.B) {
+	gen := &slowTokenGetter{delay: time.Millisecond}
+
+	g := &tokenGenerator{
+		generator:    gen,
+		tokenOptions: &token.GetTokenOptions{ClusterID: "test"},
+		options: Options{
+			ClusterName: "test",
+			debugf:      func(string, ...any) {},
+		},
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := g.Get(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}